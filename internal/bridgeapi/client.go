@@ -41,11 +41,13 @@ var BridgeProviderNS = uuid.MustParse("cc67b0e5-7152-4d54-85ff-49a5c17fbbfe")
 type ClientOption func(*Client) error
 
 type Client struct {
-	apiTarget         *url.URL
-	client            *http.Client
-	credential        TokenSource
-	useIdempotencyKey bool
-	userAgent         string
+	apiTarget             *url.URL
+	client                *http.Client
+	credential            TokenSource
+	useIdempotencyKey     bool
+	userAgent             string
+	execAllowRelativePath bool
+	operationTracker      Tracker
 }
 
 func NewClient(apiURL *url.URL, cred TokenSource, opts ...ClientOption) (*Client, error) {
@@ -55,9 +57,10 @@ func NewClient(apiURL *url.URL, cred TokenSource, opts ...ClientOption) (*Client
 
 	// Defaults unless overridden by options
 	c := &Client{
-		apiTarget:  apiURL,
-		client:     &http.Client{},
-		credential: cred,
+		apiTarget:        apiURL,
+		client:           &http.Client{},
+		credential:       cred,
+		operationTracker: tflogTracker{},
 	}
 
 	for _, opt := range opts {
@@ -97,6 +100,27 @@ func WithImmediateLogin() ClientOption {
 	}
 }
 
+// WithExecAllowRelativePath allows an ExecTokenSource to invoke a command
+// given as a relative path instead of requiring an absolute one. Absolute
+// paths are enforced by default so that a malicious or accidental PATH
+// shadow can't substitute the credential process.
+func WithExecAllowRelativePath() ClientOption {
+	return func(c *Client) error {
+		c.execAllowRelativePath = true
+		return nil
+	}
+}
+
+// WithOperationTracker overrides the Tracker that WaitForCluster reports
+// phase-transition events to when no per-call WaitOptions.Tracker is given.
+// Defaults to logging via tflog only.
+func WithOperationTracker(t Tracker) ClientOption {
+	return func(c *Client) error {
+		c.operationTracker = t
+		return nil
+	}
+}
+
 // WithIdempotencyKey causes the client to send an Idempotency Key header on cluster create
 // N.B. This may have unexpected behavior tied to cached responses after system state
 // changes invalidate the correctness of those responses
@@ -0,0 +1,159 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bridgeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrClusterNotFound is returned by GetCluster, UpdateClusterName,
+// UpgradeCluster, and DeleteCluster when the API reports no cluster exists
+// for the given id, so callers can distinguish an already-gone cluster from
+// a generic request failure (e.g. to treat it as a successful delete, or to
+// drop it from state on refresh).
+var ErrClusterNotFound = errors.New("cluster not found")
+
+// Cluster is the subset of the Crunchy Bridge cluster resource the provider
+// manages.
+type Cluster struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	TeamID          string `json:"team"`
+	PlanID          string `json:"plan"`
+	ProviderID      string `json:"provider_id"`
+	Region          string `json:"region"`
+	Storage         int    `json:"storage"`
+	IsHA            bool   `json:"is_ha"`
+	PostgresVersion int    `json:"postgres_version"`
+}
+
+// ClusterUpgradeRequest describes a change to a cluster's plan, storage, or
+// HA configuration, applied via the long-running upgrade endpoint.
+type ClusterUpgradeRequest struct {
+	PlanID  string `json:"plan,omitempty"`
+	Storage int    `json:"storage,omitempty"`
+	IsHA    *bool  `json:"is_ha,omitempty"`
+}
+
+// CreateCluster creates a new cluster. The returned Cluster is in a
+// provisioning state; pair this with WaitForCluster to block until it's
+// ready.
+func (c *Client) CreateCluster(ctx context.Context, cluster Cluster) (Cluster, error) {
+	return c.sendClusterRequest(ctx, http.MethodPost, c.resolve(routeClusters).String(), cluster)
+}
+
+// GetCluster fetches the current state of a cluster by id.
+func (c *Client) GetCluster(ctx context.Context, id string) (Cluster, error) {
+	return c.sendClusterRequest(ctx, http.MethodGet, c.resolve(fmt.Sprintf(routeCluster, id)).String(), nil)
+}
+
+// UpdateClusterName renames a cluster. This is a fast, synchronous change
+// unlike plan/storage/HA changes, which go through UpgradeCluster.
+func (c *Client) UpdateClusterName(ctx context.Context, id, name string) (Cluster, error) {
+	return c.sendClusterRequest(ctx, http.MethodPatch, c.resolve(fmt.Sprintf(routeCluster, id)).String(), struct {
+		Name string `json:"name"`
+	}{Name: name})
+}
+
+// UpgradeCluster starts a plan, storage, or HA change. The change is
+// long-running; pair this with WaitForCluster to block until it completes.
+func (c *Client) UpgradeCluster(ctx context.Context, id string, req ClusterUpgradeRequest) (Cluster, error) {
+	return c.sendClusterRequest(ctx, http.MethodPost, c.resolve(fmt.Sprintf(routeClusterUpgrade, id)).String(), req)
+}
+
+// DeleteCluster tears down a cluster.
+func (c *Client) DeleteCluster(ctx context.Context, id string) (outErr error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.resolve(fmt.Sprintf(routeCluster, id)).String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := c.setCommonHeaders(req); err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+
+	defer safeClose(&outErr, resp.Body, "response body")
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrClusterNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return errorFromAPIMessageResponse(resp)
+	}
+
+	return nil
+}
+
+func (c *Client) sendClusterRequest(ctx context.Context, method, route string, body interface{}) (_ Cluster, outErr error) {
+	var bodyReader io.Reader
+
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return Cluster{}, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+
+		bodyReader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, route, bodyReader)
+	if err != nil {
+		return Cluster{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if bodyReader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if err := c.setCommonHeaders(req); err != nil {
+		return Cluster{}, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Cluster{}, fmt.Errorf("failed to perform request: %w", err)
+	}
+
+	defer safeClose(&outErr, resp.Body, "response body")
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Cluster{}, ErrClusterNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return Cluster{}, errorFromAPIMessageResponse(resp)
+	}
+
+	var cluster Cluster
+
+	if err := json.NewDecoder(resp.Body).Decode(&cluster); err != nil {
+		return Cluster{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return cluster, nil
+}
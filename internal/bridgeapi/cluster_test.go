@@ -0,0 +1,71 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bridgeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGetCluster_NotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := c.GetCluster(context.Background(), "missing-id")
+	if !errors.Is(err, ErrClusterNotFound) {
+		t.Fatalf("expected ErrClusterNotFound, got %v", err)
+	}
+}
+
+func TestGetCluster_Success(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Cluster{ID: "abc123", Name: "my-cluster"})
+	})
+
+	cluster, err := c.GetCluster(context.Background(), "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cluster.Name != "my-cluster" {
+		t.Fatalf("got name %q, want %q", cluster.Name, "my-cluster")
+	}
+}
+
+func TestDeleteCluster_NotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	err := c.DeleteCluster(context.Background(), "missing-id")
+	if !errors.Is(err, ErrClusterNotFound) {
+		t.Fatalf("expected ErrClusterNotFound, got %v", err)
+	}
+}
+
+func TestDeleteCluster_Success(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := c.DeleteCluster(context.Background(), "abc123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bridgeapi
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExecTokenSource_RelativePathRejected(t *testing.T) {
+	e := NewExecTokenSource("sh", nil, nil, 0)
+	c := &Client{}
+
+	_, err := e.GetToken(context.Background(), c)
+	if err == nil || !strings.Contains(err.Error(), "must be an absolute path") {
+		t.Fatalf("expected absolute path error, got %v", err)
+	}
+}
+
+func TestExecTokenSource_GetToken(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found in PATH")
+	}
+
+	tests := []struct {
+		name      string
+		script    string
+		wantToken string
+		wantErr   string
+	}{
+		{
+			name:      "success",
+			script:    `echo '{"version":1,"type":"api_key","token":"tok-123"}'`,
+			wantToken: "tok-123",
+		},
+		{
+			name:    "non-zero exit surfaces stderr",
+			script:  `echo 'boom' >&2; exit 1`,
+			wantErr: "exec credential command failed: exit status 1: boom",
+		},
+		{
+			name:    "malformed json output",
+			script:  `echo 'not-json'`,
+			wantErr: "failed to parse exec credential output",
+		},
+		{
+			name:    "empty token",
+			script:  `echo '{"version":1,"type":"api_key","token":""}'`,
+			wantErr: "returned an empty token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecTokenSource("sh", []string{"-c", tt.script}, nil, 0)
+			c := &Client{execAllowRelativePath: true}
+
+			token, err := e.GetToken(context.Background(), c)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if token != tt.wantToken {
+				t.Fatalf("got token %q, want %q", token, tt.wantToken)
+			}
+		})
+	}
+}
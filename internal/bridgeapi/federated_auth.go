@@ -0,0 +1,224 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bridgeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialSource locates the short-lived OIDC subject token that
+// FederatedAuth exchanges for a Bridge access token. Exactly one of File,
+// URL, or EnvVar should be set.
+type CredentialSource struct {
+	// File reads the token from a local file, e.g. a Kubernetes
+	// projected service account token.
+	File string
+	// URL fetches the token from an HTTP endpoint, e.g. GitHub Actions'
+	// ACTIONS_ID_TOKEN_REQUEST_URL. Headers are sent with the request.
+	URL     string
+	Headers map[string]string
+	// EnvVar reads the token directly from an environment variable, e.g.
+	// GitLab CI's CI_JOB_JWT_V2.
+	EnvVar string
+}
+
+func (s CredentialSource) read(ctx context.Context, c *Client) (string, error) {
+	switch {
+	case s.File != "":
+		b, err := os.ReadFile(s.File)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file %q: %w", s.File, err)
+		}
+
+		return strings.TrimSpace(string(b)), nil
+	case s.URL != "":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create request: %w", err)
+		}
+
+		for k, v := range s.Headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch token: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return "", errorFromAPIMessageResponse(resp)
+		}
+
+		var body struct {
+			Value string `json:"value"`
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return "", fmt.Errorf("failed to unmarshal token response: %w", err)
+		}
+
+		return body.Value, nil
+	case s.EnvVar != "":
+		v, ok := os.LookupEnv(s.EnvVar)
+		if !ok {
+			return "", fmt.Errorf("environment variable %q is not set", s.EnvVar)
+		}
+
+		return v, nil
+	default:
+		return "", errors.New("no credential source configured")
+	}
+}
+
+// NewGitHubActionsCredentialSource builds a CredentialSource that requests
+// an OIDC ID token from GitHub Actions' built-in token endpoint, scoped to
+// audience.
+func NewGitHubActionsCredentialSource(audience string) (CredentialSource, error) {
+	reqURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	reqToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+
+	if reqURL == "" || reqToken == "" {
+		return CredentialSource{}, errors.New(
+			"ACTIONS_ID_TOKEN_REQUEST_URL/_TOKEN are not set, is this running in a GitHub Actions workflow with id-token: write permission?")
+	}
+
+	if audience != "" {
+		reqURL += "&audience=" + url.QueryEscape(audience)
+	}
+
+	return CredentialSource{
+		URL:     reqURL,
+		Headers: map[string]string{"Authorization": "Bearer " + reqToken},
+	}, nil
+}
+
+// NewGitLabCICredentialSource builds a CredentialSource that reads the
+// CI_JOB_JWT_V2 ID token GitLab CI injects into job environments.
+func NewGitLabCICredentialSource() CredentialSource {
+	return CredentialSource{EnvVar: "CI_JOB_JWT_V2"}
+}
+
+// NewKubernetesCredentialSource builds a CredentialSource that reads a
+// projected Kubernetes service account token from path.
+func NewKubernetesCredentialSource(path string) CredentialSource {
+	return CredentialSource{File: path}
+}
+
+// FederatedAuth exchanges a short-lived OIDC ID token for a Crunchy Bridge
+// access token, following the "external account" / workload-identity
+// federation model used by the major cloud auth libraries. This lets CI
+// pipelines authenticate without holding a long-lived API key.
+type FederatedAuth struct {
+	source           CredentialSource
+	audience         string
+	subjectTokenType string
+
+	m       sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewFederatedAuth exchanges the token read from source for a Bridge access
+// token scoped to audience, identifying the subject token as
+// subjectTokenType (e.g. "urn:ietf:params:oauth:token-type:jwt").
+func NewFederatedAuth(source CredentialSource, audience, subjectTokenType string) *FederatedAuth {
+	return &FederatedAuth{
+		source:           source,
+		audience:         audience,
+		subjectTokenType: subjectTokenType,
+	}
+}
+
+func (a *FederatedAuth) GetToken(ctx context.Context, c *Client) (_ string, outErr error) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if time.Until(a.expires) > minTimeUntilExpiry {
+		return a.token, nil
+	}
+
+	subjectToken, err := a.source.read(ctx, c)
+	if err != nil {
+		return "", fmt.Errorf("failed to read federated subject token: %w", err)
+	}
+
+	body, err := json.Marshal(struct {
+		GrantType        string `json:"grant_type"`
+		SubjectToken     string `json:"subject_token"`
+		SubjectTokenType string `json:"subject_token_type"`
+		Audience         string `json:"audience"`
+	}{
+		GrantType:        "urn:ietf:params:oauth:grant-type:token-exchange",
+		SubjectToken:     subjectToken,
+		SubjectTokenType: a.subjectTokenType,
+		Audience:         a.audience,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token exchange request: %w", err)
+	}
+
+	route := c.resolve("/access-tokens")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route.String(), bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to perform request: %w", err)
+	}
+
+	defer safeClose(&outErr, resp.Body, "response body")
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errorFromAPIMessageResponse(resp)
+	}
+
+	var tr struct {
+		ExpiresIn int64  `json:"expires_in"`
+		Token     string `json:"access_token"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	a.token = tr.Token
+	a.expires = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+
+	return a.token, nil
+}
+
+// Close is a no-op, federated access tokens are short-lived and expire on
+// their own; there's no session to tear down server-side.
+func (a *FederatedAuth) Close(ctx context.Context, c *Client) error {
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bridgeapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCredentialSource_Read(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		f, err := os.CreateTemp(t.TempDir(), "token")
+		if err != nil {
+			t.Fatalf("failed to create temp file: %v", err)
+		}
+
+		if _, err := f.WriteString("  file-token\n"); err != nil {
+			t.Fatalf("failed to write temp file: %v", err)
+		}
+
+		if err := f.Close(); err != nil {
+			t.Fatalf("failed to close temp file: %v", err)
+		}
+
+		src := CredentialSource{File: f.Name()}
+
+		tok, err := src.read(context.Background(), &Client{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if tok != "file-token" {
+			t.Fatalf("got %q, want %q", tok, "file-token")
+		}
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("CRUNCHYBRIDGE_TEST_TOKEN", "env-token")
+
+		src := CredentialSource{EnvVar: "CRUNCHYBRIDGE_TEST_TOKEN"}
+
+		tok, err := src.read(context.Background(), &Client{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if tok != "env-token" {
+			t.Fatalf("got %q, want %q", tok, "env-token")
+		}
+	})
+
+	t.Run("missing env var", func(t *testing.T) {
+		src := CredentialSource{EnvVar: "CRUNCHYBRIDGE_TEST_TOKEN_MISSING"}
+
+		if _, err := src.read(context.Background(), &Client{}); err == nil {
+			t.Fatalf("expected an error for an unset environment variable")
+		}
+	})
+
+	t.Run("url", func(t *testing.T) {
+		var gotAuth string
+
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			gotAuth = r.Header.Get("Authorization")
+			_ = json.NewEncoder(w).Encode(map[string]string{"value": "url-token"})
+		})
+
+		src := CredentialSource{
+			URL:     c.apiTarget.String(),
+			Headers: map[string]string{"Authorization": "Bearer upstream"},
+		}
+
+		tok, err := src.read(context.Background(), c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if tok != "url-token" {
+			t.Fatalf("got %q, want %q", tok, "url-token")
+		}
+
+		if gotAuth != "Bearer upstream" {
+			t.Fatalf("expected configured headers to be forwarded, got Authorization %q", gotAuth)
+		}
+	})
+
+	t.Run("no source configured", func(t *testing.T) {
+		if _, err := (CredentialSource{}).read(context.Background(), &Client{}); err == nil {
+			t.Fatalf("expected an error when no credential source is configured")
+		}
+	})
+}
+
+func TestFederatedAuth_GetToken(t *testing.T) {
+	t.Run("exchanges and caches the subject token", func(t *testing.T) {
+		var calls int
+
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode exchange request: %v", err)
+			}
+
+			if body["subject_token"] != "subject-tok" {
+				t.Errorf("expected subject token to be forwarded, got %v", body["subject_token"])
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "bridge-tok",
+				"expires_in":   3600,
+			})
+		})
+
+		t.Setenv("CRUNCHYBRIDGE_TEST_SUBJECT", "subject-tok")
+
+		a := NewFederatedAuth(CredentialSource{EnvVar: "CRUNCHYBRIDGE_TEST_SUBJECT"}, "aud", "urn:ietf:params:oauth:token-type:jwt")
+
+		tok, err := a.GetToken(context.Background(), c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if tok != "bridge-tok" {
+			t.Fatalf("got %q, want %q", tok, "bridge-tok")
+		}
+
+		if _, err := a.GetToken(context.Background(), c); err != nil {
+			t.Fatalf("unexpected error on second call: %v", err)
+		}
+
+		if calls != 1 {
+			t.Fatalf("expected the token exchange to be cached, got %d calls", calls)
+		}
+	})
+
+	t.Run("subject token read failure", func(t *testing.T) {
+		a := NewFederatedAuth(CredentialSource{EnvVar: "CRUNCHYBRIDGE_TEST_SUBJECT_MISSING"}, "aud", "jwt")
+
+		_, err := a.GetToken(context.Background(), &Client{})
+		if err == nil || !strings.Contains(err.Error(), "failed to read federated subject token") {
+			t.Fatalf("expected a subject token read error, got %v", err)
+		}
+	})
+
+	t.Run("non-200 exchange response", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+
+		t.Setenv("CRUNCHYBRIDGE_TEST_SUBJECT2", "subject-tok")
+
+		a := NewFederatedAuth(CredentialSource{EnvVar: "CRUNCHYBRIDGE_TEST_SUBJECT2"}, "aud", "jwt")
+
+		if _, err := a.GetToken(context.Background(), c); err == nil {
+			t.Fatalf("expected an error for a non-200 token exchange response")
+		}
+	})
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bridgeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Event describes a single phase transition observed while polling a
+// long-running cluster operation.
+type Event struct {
+	ClusterID string
+	Phase     string
+	Message   string
+	Err       error
+}
+
+// Tracker is notified of phase transitions as WaitForCluster polls the API.
+// The default Tracker logs via tflog; callers embedding the client can
+// supply their own, e.g. to forward events to a channel.
+type Tracker interface {
+	Notify(ctx context.Context, e Event)
+}
+
+// TrackerFunc adapts a plain function to a Tracker.
+type TrackerFunc func(ctx context.Context, e Event)
+
+func (f TrackerFunc) Notify(ctx context.Context, e Event) {
+	f(ctx, e)
+}
+
+// tflogTracker is the default Tracker, it emits structured tflog records
+// keyed by cluster id and phase so `TF_LOG=info` surfaces progress without
+// the provider needing its own polling loops.
+type tflogTracker struct{}
+
+func (tflogTracker) Notify(ctx context.Context, e Event) {
+	fields := map[string]interface{}{
+		"cluster_id": e.ClusterID,
+		"phase":      e.Phase,
+	}
+
+	if e.Err != nil {
+		fields["error"] = e.Err.Error()
+		tflog.Error(ctx, "crunchybridge cluster operation failed: "+e.Message, fields)
+
+		return
+	}
+
+	tflog.Info(ctx, "crunchybridge cluster operation progress: "+e.Message, fields)
+}
+
+// ChanTracker delivers events to a user-supplied channel in addition to
+// whatever the channel's owner wants to do with them. Sends are best
+// effort: a full channel drops the event rather than blocking the poll
+// loop.
+type ChanTracker chan<- Event
+
+func (c ChanTracker) Notify(ctx context.Context, e Event) {
+	select {
+	case c <- e:
+	default:
+	}
+}
+
+// WaitOptions tunes how WaitForCluster polls cluster status.
+type WaitOptions struct {
+	// Tracker receives phase-transition events. Defaults to the client's
+	// configured tracker (itself defaulting to tflog-only logging).
+	Tracker Tracker
+	// PollInterval is the base interval between polls absent a
+	// Retry-After header. Defaults to 5s.
+	PollInterval time.Duration
+	// MaxPollInterval bounds the exponential backoff. Defaults to 30s.
+	MaxPollInterval time.Duration
+}
+
+func (o WaitOptions) withDefaults(fallback Tracker) WaitOptions {
+	if o.Tracker == nil {
+		o.Tracker = fallback
+	}
+
+	if o.PollInterval <= 0 {
+		o.PollInterval = 5 * time.Second
+	}
+
+	if o.MaxPollInterval <= 0 {
+		o.MaxPollInterval = 30 * time.Second
+	}
+
+	return o
+}
+
+// clusterStatus mirrors the relevant fields of the /clusters/{id}/status
+// response.
+type clusterStatus struct {
+	Phase   string `json:"phase"`
+	Message string `json:"message"`
+	Failed  bool   `json:"failed"`
+}
+
+// terminalPhases are phases that end the wait loop successfully.
+var terminalPhases = map[string]bool{
+	"ready": true,
+}
+
+// WaitForCluster polls the cluster status endpoint on a jittered
+// exponential backoff until the cluster reaches a terminal phase, the
+// operation is reported as failed, or ctx is done. Phase transitions are
+// reported to opts.Tracker as they're observed, or to the client's
+// configured tracker when opts.Tracker is nil.
+func (c *Client) WaitForCluster(ctx context.Context, id string, opts WaitOptions) error {
+	opts = opts.withDefaults(c.operationTracker)
+
+	interval := opts.PollInterval
+	lastPhase := ""
+
+	for {
+		status, retryAfter, err := c.getClusterStatus(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to poll cluster status: %w", err)
+		}
+
+		if status.Phase != lastPhase {
+			opts.Tracker.Notify(ctx, Event{
+				ClusterID: id,
+				Phase:     status.Phase,
+				Message:   status.Message,
+			})
+
+			lastPhase = status.Phase
+		}
+
+		if status.Failed {
+			err := fmt.Errorf("cluster %s entered phase %q: %s", id, status.Phase, status.Message)
+
+			opts.Tracker.Notify(ctx, Event{
+				ClusterID: id,
+				Phase:     status.Phase,
+				Message:   status.Message,
+				Err:       err,
+			})
+
+			return err
+		}
+
+		if terminalPhases[status.Phase] {
+			return nil
+		}
+
+		wait := retryAfter
+		if wait <= 0 {
+			wait = jitter(interval)
+
+			interval *= 2
+			if interval > opts.MaxPollInterval {
+				interval = opts.MaxPollInterval
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func jitter(d time.Duration) time.Duration {
+	return d/2 + time.Duration(rand.Int63n(int64(d/2+1)))
+}
+
+func (c *Client) getClusterStatus(ctx context.Context, id string) (_ clusterStatus, _ time.Duration, outErr error) {
+	route := c.resolve(fmt.Sprintf(routeClusterStatus, id))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, route.String(), nil)
+	if err != nil {
+		return clusterStatus{}, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := c.setCommonHeaders(req); err != nil {
+		return clusterStatus{}, 0, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return clusterStatus{}, 0, fmt.Errorf("failed to perform request: %w", err)
+	}
+
+	defer safeClose(&outErr, resp.Body, "response body")
+
+	if resp.StatusCode != http.StatusOK {
+		return clusterStatus{}, 0, errorFromAPIMessageResponse(resp)
+	}
+
+	var status clusterStatus
+
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return clusterStatus{}, 0, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	var retryAfter time.Duration
+
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+	}
+
+	return status, retryAfter, nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bridgeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusStep describes one canned /clusters/{id}/status response. Once
+// exhausted, the handler keeps returning the last step.
+type statusStep struct {
+	phase      string
+	message    string
+	failed     bool
+	retryAfter string
+}
+
+func statusHandler(steps []statusStep) http.HandlerFunc {
+	var n int32
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&n, 1)) - 1
+		if i >= len(steps) {
+			i = len(steps) - 1
+		}
+
+		s := steps[i]
+
+		if s.retryAfter != "" {
+			w.Header().Set("Retry-After", s.retryAfter)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"phase":   s.phase,
+			"message": s.message,
+			"failed":  s.failed,
+		})
+	}
+}
+
+// eventRecorder is a Tracker that records every Event it's notified of, safe
+// for concurrent use from WaitForCluster's poll loop.
+type eventRecorder struct {
+	m      sync.Mutex
+	events []Event
+}
+
+func (r *eventRecorder) Notify(ctx context.Context, e Event) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.events = append(r.events, e)
+}
+
+func (r *eventRecorder) phases() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	phases := make([]string, len(r.events))
+	for i, e := range r.events {
+		phases[i] = e.Phase
+	}
+
+	return phases
+}
+
+func TestWaitForCluster_ReachesReady(t *testing.T) {
+	c := newTestClient(t, statusHandler([]statusStep{
+		{phase: "creating", message: "provisioning"},
+		{phase: "creating", message: "provisioning"},
+		{phase: "starting", message: "starting postgres"},
+		{phase: "ready", message: "available"},
+	}))
+
+	rec := &eventRecorder{}
+
+	err := c.WaitForCluster(context.Background(), "abc123", WaitOptions{
+		Tracker:         rec,
+		PollInterval:    5 * time.Millisecond,
+		MaxPollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"creating", "starting", "ready"}
+
+	got := rec.phases()
+	if len(got) != len(want) {
+		t.Fatalf("got phases %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got phases %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWaitForCluster_ReportsFailure(t *testing.T) {
+	c := newTestClient(t, statusHandler([]statusStep{
+		{phase: "creating", message: "provisioning"},
+		{phase: "failed", message: "out of capacity", failed: true},
+	}))
+
+	rec := &eventRecorder{}
+
+	err := c.WaitForCluster(context.Background(), "abc123", WaitOptions{
+		Tracker:         rec,
+		PollInterval:    5 * time.Millisecond,
+		MaxPollInterval: 10 * time.Millisecond,
+	})
+	if err == nil || !strings.Contains(err.Error(), "out of capacity") {
+		t.Fatalf("expected failure error mentioning the message, got %v", err)
+	}
+
+	rec.m.Lock()
+	last := rec.events[len(rec.events)-1]
+	rec.m.Unlock()
+
+	if last.Err == nil {
+		t.Fatalf("expected the final event to carry the error")
+	}
+}
+
+func TestWaitForCluster_ContextCancellation(t *testing.T) {
+	c := newTestClient(t, statusHandler([]statusStep{
+		{phase: "creating", message: "provisioning"},
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := c.WaitForCluster(ctx, "abc123", WaitOptions{
+		PollInterval:    50 * time.Millisecond,
+		MaxPollInterval: 50 * time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestGetClusterStatus_ParsesRetryAfter(t *testing.T) {
+	c := newTestClient(t, statusHandler([]statusStep{
+		{phase: "creating", message: "provisioning", retryAfter: "7"},
+	}))
+
+	status, retryAfter, err := c.getClusterStatus(context.Background(), "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if status.Phase != "creating" {
+		t.Fatalf("got phase %q, want %q", status.Phase, "creating")
+	}
+
+	if retryAfter != 7*time.Second {
+		t.Fatalf("got retryAfter %v, want %v", retryAfter, 7*time.Second)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	d := 10 * time.Second
+
+	for i := 0; i < 100; i++ {
+		j := jitter(d)
+		if j < d/2 || j > d {
+			t.Fatalf("jitter(%v) = %v, want value in [%v, %v]", d, j, d/2, d)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bridgeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// SecretFields selects which JSON keys within a secret value hold the
+// Crunchy Bridge credential, so the same secret can back either a plain
+// api_key or an application id/secret pair. A zero value uses the default
+// key names documented below.
+type SecretFields struct {
+	// APIKey names the field holding a plain API key. Defaults to "api_key".
+	APIKey string
+	// ApplicationID names the field holding the application id half of a
+	// legacy credential pair. Defaults to "application_id".
+	ApplicationID string
+	// ApplicationSecret names the field holding the application secret
+	// half of a legacy credential pair. Defaults to "application_secret".
+	ApplicationSecret string
+}
+
+func (f SecretFields) apiKeyField() string {
+	if f.APIKey != "" {
+		return f.APIKey
+	}
+
+	return "api_key"
+}
+
+func (f SecretFields) applicationIDField() string {
+	if f.ApplicationID != "" {
+		return f.ApplicationID
+	}
+
+	return "application_id"
+}
+
+func (f SecretFields) applicationSecretField() string {
+	if f.ApplicationSecret != "" {
+		return f.ApplicationSecret
+	}
+
+	return "application_secret"
+}
+
+// secretFetcher knows how to retrieve the raw bytes of a secret value from a
+// specific backend.
+type secretFetcher func(ctx context.Context) ([]byte, error)
+
+// cachedSecretAuth resolves and caches a Crunchy Bridge credential from a
+// remote secret store, delegating actual token issuance to a NewLegacyAuth
+// or APIKeyAuth built from the resolved value. The secret is only re-fetched
+// once ttl has elapsed since the last successful fetch.
+type cachedSecretAuth struct {
+	fields SecretFields
+	ttl    time.Duration
+	source string // used in wrapped error messages, e.g. "vault"
+
+	m         sync.Mutex
+	delegate  TokenSource
+	fetchedAt time.Time
+}
+
+func (a *cachedSecretAuth) getDelegate(ctx context.Context, fetch secretFetcher) (TokenSource, error) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if a.delegate != nil && time.Since(a.fetchedAt) < a.ttl {
+		return a.delegate, nil
+	}
+
+	raw, err := fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to fetch secret: %w", a.source, err)
+	}
+
+	delegate, err := a.buildDelegate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", a.source, err)
+	}
+
+	a.delegate = delegate
+	a.fetchedAt = time.Now()
+
+	return a.delegate, nil
+}
+
+func (a *cachedSecretAuth) buildDelegate(raw []byte) (TokenSource, error) {
+	var fields map[string]interface{}
+
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// Not a JSON object, treat the whole secret value as a bare API key.
+		apiKey := strings.TrimSpace(string(raw))
+		if apiKey == "" {
+			return nil, errors.New("secret value is empty")
+		}
+
+		return APIKeyAuth(apiKey), nil
+	}
+
+	if v, ok := fields[a.fields.apiKeyField()].(string); ok && v != "" {
+		return APIKeyAuth(v), nil
+	}
+
+	id, _ := fields[a.fields.applicationIDField()].(string)
+	secret, _ := fields[a.fields.applicationSecretField()].(string)
+
+	if id != "" && secret != "" {
+		return NewLegacyAuth(id, secret), nil
+	}
+
+	return nil, errors.New("secret did not contain an api_key or application id/secret pair")
+}
+
+func (a *cachedSecretAuth) close(ctx context.Context, c *Client) error {
+	a.m.Lock()
+	delegate := a.delegate
+	a.m.Unlock()
+
+	if delegate == nil {
+		return nil
+	}
+
+	return delegate.Close(ctx, c)
+}
+
+// VaultAuth resolves the Crunchy Bridge credential from a HashiCorp Vault
+// KV v2 secret, authenticating to Vault with whatever token or AppRole
+// configuration client was set up with.
+type VaultAuth struct {
+	client *vaultapi.Client
+	mount  string
+	path   string
+	cache  *cachedSecretAuth
+}
+
+// NewVaultAuth reads the secret at mount/path (KV v2) on each refresh,
+// caching the resolved credential for ttl.
+func NewVaultAuth(client *vaultapi.Client, mount, path string, fields SecretFields, ttl time.Duration) *VaultAuth {
+	return &VaultAuth{
+		client: client,
+		mount:  mount,
+		path:   path,
+		cache:  &cachedSecretAuth{fields: fields, ttl: ttl, source: "vault"},
+	}
+}
+
+func (a *VaultAuth) fetch(ctx context.Context) ([]byte, error) {
+	secret, err := a.client.KVv2(a.mount).Get(ctx, a.path)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(secret.Data)
+}
+
+func (a *VaultAuth) GetToken(ctx context.Context, c *Client) (string, error) {
+	delegate, err := a.cache.getDelegate(ctx, a.fetch)
+	if err != nil {
+		return "", err
+	}
+
+	return delegate.GetToken(ctx, c)
+}
+
+func (a *VaultAuth) Close(ctx context.Context, c *Client) error {
+	return a.cache.close(ctx, c)
+}
+
+// AWSSecretsManagerAuth resolves the Crunchy Bridge credential from an AWS
+// Secrets Manager secret.
+type AWSSecretsManagerAuth struct {
+	client   *secretsmanager.Client
+	secretID string
+	cache    *cachedSecretAuth
+}
+
+// NewAWSSecretsManagerAuth reads secretID (name or ARN) on each refresh,
+// caching the resolved credential for ttl.
+func NewAWSSecretsManagerAuth(client *secretsmanager.Client, secretID string, fields SecretFields, ttl time.Duration) *AWSSecretsManagerAuth {
+	return &AWSSecretsManagerAuth{
+		client:   client,
+		secretID: secretID,
+		cache:    &cachedSecretAuth{fields: fields, ttl: ttl, source: "aws_secrets_manager"},
+	}
+}
+
+func (a *AWSSecretsManagerAuth) fetch(ctx context.Context) ([]byte, error) {
+	out, err := a.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: &a.secretID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if out.SecretString != nil {
+		return []byte(*out.SecretString), nil
+	}
+
+	return out.SecretBinary, nil
+}
+
+func (a *AWSSecretsManagerAuth) GetToken(ctx context.Context, c *Client) (string, error) {
+	delegate, err := a.cache.getDelegate(ctx, a.fetch)
+	if err != nil {
+		return "", err
+	}
+
+	return delegate.GetToken(ctx, c)
+}
+
+func (a *AWSSecretsManagerAuth) Close(ctx context.Context, c *Client) error {
+	return a.cache.close(ctx, c)
+}
+
+// GCPSecretManagerAuth resolves the Crunchy Bridge credential from a Google
+// Cloud Secret Manager secret version.
+type GCPSecretManagerAuth struct {
+	client *secretmanager.Client
+	name   string // e.g. "projects/p/secrets/s/versions/latest"
+	cache  *cachedSecretAuth
+}
+
+// NewGCPSecretManagerAuth accesses the secret version name on each refresh,
+// caching the resolved credential for ttl.
+func NewGCPSecretManagerAuth(client *secretmanager.Client, name string, fields SecretFields, ttl time.Duration) *GCPSecretManagerAuth {
+	return &GCPSecretManagerAuth{
+		client: client,
+		name:   name,
+		cache:  &cachedSecretAuth{fields: fields, ttl: ttl, source: "gcp_secret_manager"},
+	}
+}
+
+func (a *GCPSecretManagerAuth) fetch(ctx context.Context) ([]byte, error) {
+	resp, err := a.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+		Name: a.name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetPayload().GetData(), nil
+}
+
+func (a *GCPSecretManagerAuth) GetToken(ctx context.Context, c *Client) (string, error) {
+	delegate, err := a.cache.getDelegate(ctx, a.fetch)
+	if err != nil {
+		return "", err
+	}
+
+	return delegate.GetToken(ctx, c)
+}
+
+func (a *GCPSecretManagerAuth) Close(ctx context.Context, c *Client) error {
+	return a.cache.close(ctx, c)
+}
+
+// AzureKeyVaultAuth resolves the Crunchy Bridge credential from an Azure Key
+// Vault secret.
+type AzureKeyVaultAuth struct {
+	client  *azsecrets.Client
+	name    string
+	version string
+	cache   *cachedSecretAuth
+}
+
+// NewAzureKeyVaultAuth gets the secret named name (version may be empty for
+// the latest version) on each refresh, caching the resolved credential for
+// ttl.
+func NewAzureKeyVaultAuth(client *azsecrets.Client, name, version string, fields SecretFields, ttl time.Duration) *AzureKeyVaultAuth {
+	return &AzureKeyVaultAuth{
+		client:  client,
+		name:    name,
+		version: version,
+		cache:   &cachedSecretAuth{fields: fields, ttl: ttl, source: "azure_key_vault"},
+	}
+}
+
+func (a *AzureKeyVaultAuth) fetch(ctx context.Context) ([]byte, error) {
+	resp, err := a.client.GetSecret(ctx, a.name, a.version, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Value == nil {
+		return nil, errors.New("secret has no value")
+	}
+
+	return []byte(*resp.Value), nil
+}
+
+func (a *AzureKeyVaultAuth) GetToken(ctx context.Context, c *Client) (string, error) {
+	delegate, err := a.cache.getDelegate(ctx, a.fetch)
+	if err != nil {
+		return "", err
+	}
+
+	return delegate.GetToken(ctx, c)
+}
+
+func (a *AzureKeyVaultAuth) Close(ctx context.Context, c *Client) error {
+	return a.cache.close(ctx, c)
+}
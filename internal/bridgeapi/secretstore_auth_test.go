@@ -0,0 +1,155 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bridgeapi
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachedSecretAuth_BuildDelegate(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		fields   SecretFields
+		wantKind string // "apikey", "legacy", or "error"
+	}{
+		{
+			name:     "non-json secret is treated as a bare api key",
+			raw:      "  plain-key  ",
+			wantKind: "apikey",
+		},
+		{
+			name:     "json api_key field, default name",
+			raw:      `{"api_key":"abc"}`,
+			wantKind: "apikey",
+		},
+		{
+			name:     "json api key field, custom name",
+			raw:      `{"my_key":"abc"}`,
+			fields:   SecretFields{APIKey: "my_key"},
+			wantKind: "apikey",
+		},
+		{
+			name:     "json id/secret pair, default field names",
+			raw:      `{"application_id":"id","application_secret":"sec"}`,
+			wantKind: "legacy",
+		},
+		{
+			name:     "json id/secret pair, custom field names",
+			raw:      `{"aid":"id","asec":"sec"}`,
+			fields:   SecretFields{ApplicationID: "aid", ApplicationSecret: "asec"},
+			wantKind: "legacy",
+		},
+		{
+			name:     "empty bare secret is an error",
+			raw:      "   ",
+			wantKind: "error",
+		},
+		{
+			name:     "json object missing every recognized field is an error",
+			raw:      `{"foo":"bar"}`,
+			wantKind: "error",
+		},
+		{
+			name:     "id without secret is an error",
+			raw:      `{"application_id":"id"}`,
+			wantKind: "error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &cachedSecretAuth{fields: tt.fields, source: "test"}
+
+			got, err := a.buildDelegate([]byte(tt.raw))
+
+			switch tt.wantKind {
+			case "error":
+				if err == nil {
+					t.Fatalf("expected an error, got delegate %v", got)
+				}
+			case "apikey":
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if _, ok := got.(APIKeyAuth); !ok {
+					t.Fatalf("expected APIKeyAuth, got %T", got)
+				}
+			case "legacy":
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if _, ok := got.(*LegacyAuth); !ok {
+					t.Fatalf("expected *LegacyAuth, got %T", got)
+				}
+			}
+		})
+	}
+}
+
+func TestCachedSecretAuth_GetDelegate_CachesUntilTTL(t *testing.T) {
+	var calls int32
+
+	fetch := func(ctx context.Context) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return []byte(`{"api_key":"abc"}`), nil
+	}
+
+	a := &cachedSecretAuth{ttl: 20 * time.Millisecond, source: "test"}
+
+	if _, err := a.getDelegate(context.Background(), fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := a.getDelegate(context.Background(), fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected fetch to be called once while cached, got %d", got)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := a.getDelegate(context.Background(), fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected fetch to be called again after ttl expiry, got %d", got)
+	}
+}
+
+func TestCachedSecretAuth_GetDelegate_FetchError(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	fetch := func(ctx context.Context) ([]byte, error) {
+		return nil, wantErr
+	}
+
+	a := &cachedSecretAuth{source: "test"}
+
+	_, err := a.getDelegate(context.Background(), fetch)
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected wrapped fetch error, got %v", err)
+	}
+}
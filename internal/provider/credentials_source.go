@@ -0,0 +1,169 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/CrunchyData/terraform-provider-crunchybridge/internal/bridgeapi"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// buildSecretTokenSource constructs the TokenSource described by a single
+// credentials_source block, connecting to whichever backend "type" selects.
+func buildSecretTokenSource(ctx context.Context, cfg map[string]interface{}) (bridgeapi.TokenSource, diag.Diagnostics) {
+	sourceType := cfg["type"].(string)
+	ttl := time.Duration(cfg["ttl_seconds"].(int)) * time.Second
+
+	fields := bridgeapi.SecretFields{
+		APIKey:            cfg["field_api_key"].(string),
+		ApplicationID:     cfg["field_application_id"].(string),
+		ApplicationSecret: cfg["field_application_secret"].(string),
+	}
+
+	switch sourceType {
+	case "vault":
+		return buildVaultAuth(ctx, cfg, fields, ttl)
+	case "aws_secrets_manager":
+		return buildAWSSecretsManagerAuth(ctx, cfg, fields, ttl)
+	case "gcp_secret_manager":
+		return buildGCPSecretManagerAuth(ctx, cfg, fields, ttl)
+	case "azure_key_vault":
+		return buildAzureKeyVaultAuth(cfg, fields, ttl)
+	default:
+		return nil, diag.Errorf(
+			"%s: unknown %q %q, must be one of vault, aws_secrets_manager, gcp_secret_manager, azure_key_vault",
+			credentialsSourceName, "type", sourceType)
+	}
+}
+
+func firstBlock(cfg map[string]interface{}, key string) (map[string]interface{}, bool) {
+	list := cfg[key].([]interface{})
+	if len(list) == 0 {
+		return nil, false
+	}
+
+	return list[0].(map[string]interface{}), true
+}
+
+func buildVaultAuth(ctx context.Context, cfg map[string]interface{}, fields bridgeapi.SecretFields, ttl time.Duration) (bridgeapi.TokenSource, diag.Diagnostics) {
+	vc, ok := firstBlock(cfg, "vault")
+	if !ok {
+		return nil, diag.Errorf("vault: a %q block is required when type = \"vault\"", "vault")
+	}
+
+	vaultCfg := vaultapi.DefaultConfig()
+
+	if address, _ := vc["address"].(string); address != "" {
+		vaultCfg.Address = address
+	}
+
+	client, err := vaultapi.NewClient(vaultCfg)
+	if err != nil {
+		return nil, diag.Errorf("vault: failed to build client: %v", err)
+	}
+
+	if token, _ := vc["token"].(string); token != "" {
+		client.SetToken(token)
+	} else if roleID, _ := vc["role_id"].(string); roleID != "" {
+		secretID, _ := vc["secret_id"].(string)
+
+		secret, err := client.Logical().WriteWithContext(ctx, "auth/approle/login", map[string]interface{}{
+			"role_id":   roleID,
+			"secret_id": secretID,
+		})
+		if err != nil {
+			return nil, diag.Errorf("vault: AppRole login failed: %v", err)
+		}
+
+		if secret == nil || secret.Auth == nil {
+			return nil, diag.Errorf("vault: AppRole login returned no auth information")
+		}
+
+		client.SetToken(secret.Auth.ClientToken)
+	}
+
+	return bridgeapi.NewVaultAuth(client, vc["mount"].(string), vc["path"].(string), fields, ttl), nil
+}
+
+func buildAWSSecretsManagerAuth(ctx context.Context, cfg map[string]interface{}, fields bridgeapi.SecretFields, ttl time.Duration) (bridgeapi.TokenSource, diag.Diagnostics) {
+	ac, ok := firstBlock(cfg, "aws_secrets_manager")
+	if !ok {
+		return nil, diag.Errorf("aws_secrets_manager: an %q block is required when type = \"aws_secrets_manager\"", "aws_secrets_manager")
+	}
+
+	var opts []func(*config.LoadOptions) error
+
+	if region, _ := ac["region"].(string); region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, diag.Errorf("aws_secrets_manager: failed to load AWS config: %v", err)
+	}
+
+	client := secretsmanager.NewFromConfig(awsCfg)
+
+	return bridgeapi.NewAWSSecretsManagerAuth(client, ac["secret_id"].(string), fields, ttl), nil
+}
+
+func buildGCPSecretManagerAuth(ctx context.Context, cfg map[string]interface{}, fields bridgeapi.SecretFields, ttl time.Duration) (bridgeapi.TokenSource, diag.Diagnostics) {
+	gc, ok := firstBlock(cfg, "gcp_secret_manager")
+	if !ok {
+		return nil, diag.Errorf("gcp_secret_manager: a %q block is required when type = \"gcp_secret_manager\"", "gcp_secret_manager")
+	}
+
+	client, err := secretmanager.NewClient(ctx)
+	if err != nil {
+		return nil, diag.Errorf("gcp_secret_manager: failed to build client: %v", err)
+	}
+
+	return bridgeapi.NewGCPSecretManagerAuth(client, gc["name"].(string), fields, ttl), nil
+}
+
+func buildAzureKeyVaultAuth(cfg map[string]interface{}, fields bridgeapi.SecretFields, ttl time.Duration) (bridgeapi.TokenSource, diag.Diagnostics) {
+	kc, ok := firstBlock(cfg, "azure_key_vault")
+	if !ok {
+		return nil, diag.Errorf("azure_key_vault: an %q block is required when type = \"azure_key_vault\"", "azure_key_vault")
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, diag.Errorf("azure_key_vault: failed to build credential: %v", err)
+	}
+
+	client, err := azsecrets.NewClient(kc["vault_url"].(string), cred, &azsecrets.ClientOptions{
+		ClientOptions: azcore.ClientOptions{},
+	})
+	if err != nil {
+		return nil, diag.Errorf("azure_key_vault: failed to build client: %v", err)
+	}
+
+	version, _ := kc["version"].(string)
+
+	return bridgeapi.NewAzureKeyVaultAuth(client, kc["name"].(string), version, fields, ttl), nil
+}
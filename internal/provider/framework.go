@@ -0,0 +1,413 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/CrunchyData/terraform-provider-crunchybridge/internal/bridgeapi"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ provider.Provider = (*bridgeProvider)(nil)
+
+// bridgeProvider is the terraform-plugin-framework counterpart to the
+// schema.Provider built by New. It's served side-by-side with the SDKv2
+// provider behind a protocol mux (see MuxServer) while resources and data
+// sources are ported across one at a time, so resources/datasources stays
+// empty until a port lands.
+//
+// tf6muxserver requires every muxed server to report an identical
+// provider-level schema, so Schema below mirrors New's schema.Provider.Schema
+// attribute-for-attribute, including the exec, credentials_source, and oidc
+// blocks that Configure doesn't act on yet. Those blocks only take effect
+// once a framework-managed resource or data source is actually configured
+// through this provider; until then Configure authenticates the same way
+// New's configure does for the api_key and application_id/secret fields
+// (including their environment-variable defaults), and returns without a
+// client when only one of those blocks supplies credentials. bridgeapi_url
+// has no default here.
+type bridgeProvider struct {
+	version string
+}
+
+// bridgeProviderModel mirrors the provider-level fields of New's Schema.
+type bridgeProviderModel struct {
+	APIKey            types.String                  `tfsdk:"api_key"`
+	ApplicationID     types.String                  `tfsdk:"application_id"`
+	ApplicationSecret types.String                  `tfsdk:"application_secret"`
+	ImmediateLogin    types.Bool                    `tfsdk:"immediate_login"`
+	BridgeAPIURL      types.String                  `tfsdk:"bridgeapi_url"`
+	Exec              []execBlockModel              `tfsdk:"exec"`
+	CredentialsSource []credentialsSourceBlockModel `tfsdk:"credentials_source"`
+	OIDC              []oidcBlockModel              `tfsdk:"oidc"`
+}
+
+// execBlockModel mirrors the exec block of New's Schema.
+type execBlockModel struct {
+	Command           types.String `tfsdk:"command"`
+	Args              types.List   `tfsdk:"args"`
+	Env               types.Map    `tfsdk:"env"`
+	TimeoutSeconds    types.Int64  `tfsdk:"timeout_seconds"`
+	AllowRelativePath types.Bool   `tfsdk:"allow_relative_path"`
+}
+
+// credentialsSourceBlockModel mirrors the credentials_source block of New's
+// Schema.
+type credentialsSourceBlockModel struct {
+	Type                   types.String                  `tfsdk:"type"`
+	TTLSeconds             types.Int64                   `tfsdk:"ttl_seconds"`
+	FieldAPIKey            types.String                  `tfsdk:"field_api_key"`
+	FieldApplicationID     types.String                  `tfsdk:"field_application_id"`
+	FieldApplicationSecret types.String                  `tfsdk:"field_application_secret"`
+	Vault                  []vaultBlockModel             `tfsdk:"vault"`
+	AWSSecretsManager      []awsSecretsManagerBlockModel `tfsdk:"aws_secrets_manager"`
+	GCPSecretManager       []gcpSecretManagerBlockModel  `tfsdk:"gcp_secret_manager"`
+	AzureKeyVault          []azureKeyVaultBlockModel     `tfsdk:"azure_key_vault"`
+}
+
+// vaultBlockModel mirrors the credentials_source.vault block of New's Schema.
+type vaultBlockModel struct {
+	Address  types.String `tfsdk:"address"`
+	Token    types.String `tfsdk:"token"`
+	RoleID   types.String `tfsdk:"role_id"`
+	SecretID types.String `tfsdk:"secret_id"`
+	Mount    types.String `tfsdk:"mount"`
+	Path     types.String `tfsdk:"path"`
+}
+
+// awsSecretsManagerBlockModel mirrors the
+// credentials_source.aws_secrets_manager block of New's Schema.
+type awsSecretsManagerBlockModel struct {
+	SecretID types.String `tfsdk:"secret_id"`
+	Region   types.String `tfsdk:"region"`
+}
+
+// gcpSecretManagerBlockModel mirrors the credentials_source.gcp_secret_manager
+// block of New's Schema.
+type gcpSecretManagerBlockModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
+// azureKeyVaultBlockModel mirrors the credentials_source.azure_key_vault
+// block of New's Schema.
+type azureKeyVaultBlockModel struct {
+	VaultURL types.String `tfsdk:"vault_url"`
+	Name     types.String `tfsdk:"name"`
+	Version  types.String `tfsdk:"version"`
+}
+
+// oidcBlockModel mirrors the oidc block of New's Schema.
+type oidcBlockModel struct {
+	Audience         types.String `tfsdk:"audience"`
+	SubjectTokenType types.String `tfsdk:"subject_token_type"`
+	TokenURL         types.String `tfsdk:"token_url"`
+	TokenFile        types.String `tfsdk:"token_file"`
+	TokenEnv         types.String `tfsdk:"token_env"`
+	Headers          types.Map    `tfsdk:"headers"`
+}
+
+func newFrameworkProvider(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &bridgeProvider{version: version}
+	}
+}
+
+func (p *bridgeProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "crunchybridge"
+	resp.Version = p.version
+}
+
+func (p *bridgeProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			apiKeyConfigName: schema.StringAttribute{
+				Optional:    true,
+				Description: "The Crunchy Bridge API key.",
+			},
+			idConfigName: schema.StringAttribute{
+				Optional:    true,
+				Description: "The application id component of the Crunchy Bridge API key.",
+			},
+			secretConfigName: schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The application secret component of the Crunchy Bridge API key.",
+			},
+			immediateLoginConfigName: schema.BoolAttribute{
+				Optional: true,
+				Description: fmt.Sprintf("When true, %q and %q will be validated when the provider is configured.",
+					idConfigName, secretConfigName),
+			},
+			urlConfigName: schema.StringAttribute{
+				Required:    true,
+				Description: "The API URL for the Crunchy Bridge platform API. Most users should not need to change this value.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			execConfigName: schema.ListNestedBlock{
+				Description: "Obtain credentials by invoking an external command, mirroring AWS's `credential_process` and the executable-sourced credentials used by Google's auth libraries. Mutually exclusive with the other authentication inputs.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"command": schema.StringAttribute{
+							Required:    true,
+							Description: "Path to the executable to invoke for credentials. Must be absolute unless `allow_relative_path` is set.",
+						},
+						"args": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Arguments passed to the command.",
+						},
+						"env": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Additional environment variables passed to the command, on top of the CRUNCHYBRIDGE_EXEC_* variables it always receives.",
+						},
+						"timeout_seconds": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Maximum number of seconds to let the command run before it is killed.",
+						},
+						"allow_relative_path": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Allow `command` to be a relative path instead of requiring an absolute one.",
+						},
+					},
+				},
+			},
+			credentialsSourceName: schema.ListNestedBlock{
+				Description: "Resolve the Crunchy Bridge credential from a remote secret store instead of passing it directly. Mutually exclusive with the other authentication inputs.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Required:    true,
+							Description: "One of `vault`, `aws_secrets_manager`, `gcp_secret_manager`, `azure_key_vault`.",
+						},
+						"ttl_seconds": schema.Int64Attribute{
+							Optional:    true,
+							Description: "How long a resolved credential is cached before the secret store is queried again.",
+						},
+						"field_api_key": schema.StringAttribute{
+							Optional:    true,
+							Description: "JSON field within the secret holding a plain API key. Defaults to `api_key`.",
+						},
+						"field_application_id": schema.StringAttribute{
+							Optional:    true,
+							Description: "JSON field within the secret holding the application id half of a credential pair. Defaults to `application_id`.",
+						},
+						"field_application_secret": schema.StringAttribute{
+							Optional:    true,
+							Description: "JSON field within the secret holding the application secret half of a credential pair. Defaults to `application_secret`.",
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"vault": schema.ListNestedBlock{
+							Description: "Configuration for `type = \"vault\"`: a HashiCorp Vault KV v2 secret.",
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"address": schema.StringAttribute{
+										Optional:    true,
+										Description: "Vault server address. Defaults to the `VAULT_ADDR` environment variable.",
+									},
+									"token": schema.StringAttribute{
+										Optional:    true,
+										Sensitive:   true,
+										Description: "Vault token. Defaults to the `VAULT_TOKEN` environment variable.",
+									},
+									"role_id": schema.StringAttribute{
+										Optional:    true,
+										Description: "AppRole role id, used instead of `token`.",
+									},
+									"secret_id": schema.StringAttribute{
+										Optional:    true,
+										Sensitive:   true,
+										Description: "AppRole secret id, used instead of `token`.",
+									},
+									"mount": schema.StringAttribute{
+										Required:    true,
+										Description: "KV v2 mount path.",
+									},
+									"path": schema.StringAttribute{
+										Required:    true,
+										Description: "Secret path within the mount.",
+									},
+								},
+							},
+						},
+						"aws_secrets_manager": schema.ListNestedBlock{
+							Description: "Configuration for `type = \"aws_secrets_manager\"`.",
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"secret_id": schema.StringAttribute{
+										Required:    true,
+										Description: "Secret name or ARN.",
+									},
+									"region": schema.StringAttribute{
+										Optional:    true,
+										Description: "AWS region. Defaults to the environment/shared configuration.",
+									},
+								},
+							},
+						},
+						"gcp_secret_manager": schema.ListNestedBlock{
+							Description: "Configuration for `type = \"gcp_secret_manager\"`.",
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"name": schema.StringAttribute{
+										Required:    true,
+										Description: "Full secret version resource name, e.g. `projects/p/secrets/s/versions/latest`.",
+									},
+								},
+							},
+						},
+						"azure_key_vault": schema.ListNestedBlock{
+							Description: "Configuration for `type = \"azure_key_vault\"`.",
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"vault_url": schema.StringAttribute{
+										Required:    true,
+										Description: "Key Vault URL, e.g. `https://my-vault.vault.azure.net`.",
+									},
+									"name": schema.StringAttribute{
+										Required:    true,
+										Description: "Secret name.",
+									},
+									"version": schema.StringAttribute{
+										Optional:    true,
+										Description: "Secret version. Defaults to the latest version.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			oidcConfigName: schema.ListNestedBlock{
+				Description: "Exchange a short-lived OIDC ID token for Bridge credentials, following the workload-identity federation model used by the major cloud providers. Mutually exclusive with the other authentication inputs.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"audience": schema.StringAttribute{
+							Optional:    true,
+							Description: "Audience sent with the federated token exchange request. It is not added to the `token_url` request.",
+						},
+						"subject_token_type": schema.StringAttribute{
+							Optional:    true,
+							Description: "The subject_token_type sent with the token exchange request.",
+						},
+						"token_url": schema.StringAttribute{
+							Optional:    true,
+							Description: "Fetch the subject token from this URL, e.g. GitHub Actions' `ACTIONS_ID_TOKEN_REQUEST_URL`.",
+						},
+						"token_file": schema.StringAttribute{
+							Optional:    true,
+							Description: "Read the subject token from this file, e.g. a projected Kubernetes service account token.",
+						},
+						"token_env": schema.StringAttribute{
+							Optional:    true,
+							Description: "Read the subject token from this environment variable, e.g. GitLab CI's `CI_JOB_JWT_V2`.",
+						},
+						"headers": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Additional headers sent when `token_url` is used, e.g. an Authorization header for GitHub Actions.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (p *bridgeProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var data bridgeProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := data.ApplicationID.ValueString()
+	secret := data.ApplicationSecret.ValueString()
+	apiKey := data.APIKey.ValueString()
+
+	// Same environment-variable defaults as New's schema.
+	if id == "" {
+		id = os.Getenv("APPLICATION_ID")
+	}
+	if secret == "" {
+		secret = os.Getenv("APPLICATION_SECRET")
+	}
+	if apiKey == "" {
+		apiKey = os.Getenv("API_KEY")
+	}
+
+	var token bridgeapi.TokenSource
+
+	switch {
+	case id != "" && secret != "":
+		token = bridgeapi.NewLegacyAuth(id, secret)
+	case apiKey != "":
+		token = bridgeapi.APIKeyAuth(apiKey)
+	case len(data.Exec) > 0 || len(data.CredentialsSource) > 0 || len(data.OIDC) > 0:
+		// Not acted on here; New's configure builds the client for these.
+		return
+	default:
+		resp.Diagnostics.AddError(
+			"Missing authentication configuration",
+			fmt.Sprintf("either supply %q or %q and %q for authentication",
+				apiKeyConfigName, idConfigName, secretConfigName))
+
+		return
+	}
+
+	apiURL, err := url.Parse(data.BridgeAPIURL.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Invalid %q", urlConfigName), err.Error())
+		return
+	}
+
+	options := []bridgeapi.ClientOption{
+		bridgeapi.WithUserAgent(fmt.Sprintf("terraform-provider-crunchybridge/%s", p.version)),
+	}
+
+	if data.ImmediateLogin.ValueBool() {
+		options = append(options, bridgeapi.WithImmediateLogin())
+	}
+
+	c, err := bridgeapi.NewClient(apiURL, token, options...)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to configure client", err.Error())
+		return
+	}
+
+	resp.DataSourceData = c
+	resp.ResourceData = c
+}
+
+func (p *bridgeProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *bridgeProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+// MuxServer serves the legacy SDKv2 provider built by New (upgraded to
+// protocol 6) and the terraform-plugin-framework provider built by
+// newFrameworkProvider side-by-side behind a single protocol 6 server. This
+// is stage one of the migration off terraform-plugin-sdk/v2: resources and
+// data sources move from New to newFrameworkProvider one at a time in
+// follow-up changes, and until a given type is ported it continues to be
+// served exclusively by the upgraded SDKv2 provider.
+func MuxServer(ctx context.Context, version string) (func() tfprotov6.ProviderServer, error) {
+	upgradedSDKProvider, err := tf5to6server.UpgradeServer(ctx, New(version))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade SDKv2 provider to protocol 6: %w", err)
+	}
+
+	mux, err := tf6muxserver.NewMuxServer(ctx,
+		func() tfprotov6.ProviderServer { return upgradedSDKProvider },
+		providerserver.NewProtocol6(newFrameworkProvider(version)()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build provider mux server: %w", err)
+	}
+
+	return mux.ProviderServer, nil
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/CrunchyData/terraform-provider-crunchybridge/internal/bridgeapi"
 
@@ -32,6 +33,9 @@ const (
 	apiKeyConfigName         = "api_key"
 	urlConfigName            = "bridgeapi_url"
 	immediateLoginConfigName = "immediate_login"
+	execConfigName           = "exec"
+	credentialsSourceName    = "credentials_source"
+	oidcConfigName           = "oidc"
 )
 
 func init() {
@@ -66,22 +70,25 @@ func New(version string) func() *schema.Provider {
 			},
 			Schema: map[string]*schema.Schema{
 				apiKeyConfigName: {
-					Type:        schema.TypeString,
-					Description: "The application id component of the Crunchy Bridge API key.",
-					DefaultFunc: schema.EnvDefaultFunc("API_KEY", ""),
-					Optional:    true,
+					Type:          schema.TypeString,
+					Description:   "The Crunchy Bridge API key.",
+					DefaultFunc:   schema.EnvDefaultFunc("API_KEY", ""),
+					Optional:      true,
+					ConflictsWith: []string{idConfigName, secretConfigName, execConfigName, credentialsSourceName, oidcConfigName},
 				},
 				idConfigName: {
-					Type:        schema.TypeString,
-					Description: "The application id component of the Crunchy Bridge API key.",
-					DefaultFunc: schema.EnvDefaultFunc("APPLICATION_ID", ""),
-					Optional:    true,
+					Type:          schema.TypeString,
+					Description:   "The application id component of the Crunchy Bridge API key.",
+					DefaultFunc:   schema.EnvDefaultFunc("APPLICATION_ID", ""),
+					Optional:      true,
+					ConflictsWith: []string{apiKeyConfigName, execConfigName, credentialsSourceName, oidcConfigName},
 				},
 				secretConfigName: {
-					Type:        schema.TypeString,
-					Description: "The application secret component of the Crunchy Bridge API key.",
-					DefaultFunc: schema.EnvDefaultFunc("APPLICATION_SECRET", ""),
-					Optional:    true,
+					Type:          schema.TypeString,
+					Description:   "The application secret component of the Crunchy Bridge API key.",
+					DefaultFunc:   schema.EnvDefaultFunc("APPLICATION_SECRET", ""),
+					Optional:      true,
+					ConflictsWith: []string{apiKeyConfigName, execConfigName, credentialsSourceName, oidcConfigName},
 				},
 				immediateLoginConfigName: {
 					Type: schema.TypeBool,
@@ -95,6 +102,227 @@ func New(version string) func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("BRIDGE_API_URL", "https://api.crunchybridge.com"),
 					Required:    true,
 				},
+				execConfigName: {
+					Type:          schema.TypeList,
+					Optional:      true,
+					MaxItems:      1,
+					ConflictsWith: []string{apiKeyConfigName, idConfigName, secretConfigName, credentialsSourceName, oidcConfigName},
+					Description:   "Obtain credentials by invoking an external command, mirroring AWS's `credential_process` and the executable-sourced credentials used by Google's auth libraries. Mutually exclusive with the other authentication inputs.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"command": {
+								Type:        schema.TypeString,
+								Required:    true,
+								Description: "Path to the executable to invoke for credentials. Must be absolute unless `allow_relative_path` is set.",
+							},
+							"args": {
+								Type:        schema.TypeList,
+								Optional:    true,
+								Elem:        &schema.Schema{Type: schema.TypeString},
+								Description: "Arguments passed to the command.",
+							},
+							"env": {
+								Type:        schema.TypeMap,
+								Optional:    true,
+								Elem:        &schema.Schema{Type: schema.TypeString},
+								Description: "Additional environment variables passed to the command, on top of the CRUNCHYBRIDGE_EXEC_* variables it always receives.",
+							},
+							"timeout_seconds": {
+								Type:        schema.TypeInt,
+								Optional:    true,
+								Default:     30,
+								Description: "Maximum number of seconds to let the command run before it is killed.",
+							},
+							"allow_relative_path": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Description: "Allow `command` to be a relative path instead of requiring an absolute one.",
+							},
+						},
+					},
+				},
+				credentialsSourceName: {
+					Type:          schema.TypeList,
+					Optional:      true,
+					MaxItems:      1,
+					ConflictsWith: []string{apiKeyConfigName, idConfigName, secretConfigName, execConfigName, oidcConfigName},
+					Description:   "Resolve the Crunchy Bridge credential from a remote secret store instead of passing it directly. Mutually exclusive with the other authentication inputs.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"type": {
+								Type:        schema.TypeString,
+								Required:    true,
+								Description: "One of `vault`, `aws_secrets_manager`, `gcp_secret_manager`, `azure_key_vault`.",
+							},
+							"ttl_seconds": {
+								Type:        schema.TypeInt,
+								Optional:    true,
+								Default:     300,
+								Description: "How long a resolved credential is cached before the secret store is queried again.",
+							},
+							"field_api_key": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "JSON field within the secret holding a plain API key. Defaults to `api_key`.",
+							},
+							"field_application_id": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "JSON field within the secret holding the application id half of a credential pair. Defaults to `application_id`.",
+							},
+							"field_application_secret": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "JSON field within the secret holding the application secret half of a credential pair. Defaults to `application_secret`.",
+							},
+							"vault": {
+								Type:        schema.TypeList,
+								Optional:    true,
+								MaxItems:    1,
+								Description: "Configuration for `type = \"vault\"`: a HashiCorp Vault KV v2 secret.",
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"address": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Description: "Vault server address. Defaults to the `VAULT_ADDR` environment variable.",
+										},
+										"token": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Sensitive:   true,
+											Description: "Vault token. Defaults to the `VAULT_TOKEN` environment variable.",
+										},
+										"role_id": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Description: "AppRole role id, used instead of `token`.",
+										},
+										"secret_id": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Sensitive:   true,
+											Description: "AppRole secret id, used instead of `token`.",
+										},
+										"mount": {
+											Type:        schema.TypeString,
+											Required:    true,
+											Description: "KV v2 mount path.",
+										},
+										"path": {
+											Type:        schema.TypeString,
+											Required:    true,
+											Description: "Secret path within the mount.",
+										},
+									},
+								},
+							},
+							"aws_secrets_manager": {
+								Type:        schema.TypeList,
+								Optional:    true,
+								MaxItems:    1,
+								Description: "Configuration for `type = \"aws_secrets_manager\"`.",
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"secret_id": {
+											Type:        schema.TypeString,
+											Required:    true,
+											Description: "Secret name or ARN.",
+										},
+										"region": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Description: "AWS region. Defaults to the environment/shared configuration.",
+										},
+									},
+								},
+							},
+							"gcp_secret_manager": {
+								Type:        schema.TypeList,
+								Optional:    true,
+								MaxItems:    1,
+								Description: "Configuration for `type = \"gcp_secret_manager\"`.",
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"name": {
+											Type:        schema.TypeString,
+											Required:    true,
+											Description: "Full secret version resource name, e.g. `projects/p/secrets/s/versions/latest`.",
+										},
+									},
+								},
+							},
+							"azure_key_vault": {
+								Type:        schema.TypeList,
+								Optional:    true,
+								MaxItems:    1,
+								Description: "Configuration for `type = \"azure_key_vault\"`.",
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"vault_url": {
+											Type:        schema.TypeString,
+											Required:    true,
+											Description: "Key Vault URL, e.g. `https://my-vault.vault.azure.net`.",
+										},
+										"name": {
+											Type:        schema.TypeString,
+											Required:    true,
+											Description: "Secret name.",
+										},
+										"version": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Description: "Secret version. Defaults to the latest version.",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				oidcConfigName: {
+					Type:          schema.TypeList,
+					Optional:      true,
+					MaxItems:      1,
+					ConflictsWith: []string{apiKeyConfigName, idConfigName, secretConfigName, execConfigName, credentialsSourceName},
+					Description:   "Exchange a short-lived OIDC ID token for Bridge credentials, following the workload-identity federation model used by the major cloud providers. Mutually exclusive with the other authentication inputs.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"audience": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "Audience sent with the federated token exchange request. It is not added to the `token_url` request.",
+							},
+							"subject_token_type": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Default:     "urn:ietf:params:oauth:token-type:jwt",
+								Description: "The subject_token_type sent with the token exchange request.",
+							},
+							"token_url": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "Fetch the subject token from this URL, e.g. GitHub Actions' `ACTIONS_ID_TOKEN_REQUEST_URL`.",
+							},
+							"token_file": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "Read the subject token from this file, e.g. a projected Kubernetes service account token.",
+							},
+							"token_env": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "Read the subject token from this environment variable, e.g. GitLab CI's `CI_JOB_JWT_V2`.",
+							},
+							"headers": {
+								Type:        schema.TypeMap,
+								Optional:    true,
+								Elem:        &schema.Schema{Type: schema.TypeString},
+								Description: "Additional headers sent when `token_url` is used, e.g. an Authorization header for GitHub Actions.",
+							},
+						},
+					},
+				},
 			},
 		}
 
@@ -115,18 +343,66 @@ func configure(version string, p *schema.Provider) func(context.Context, *schema
 		secret := d.Get(secretConfigName).(string)
 		apiKey := d.Get(apiKeyConfigName).(string)
 		immediateLogin := d.Get(immediateLoginConfigName).(bool)
+		execCfg := d.Get(execConfigName).([]interface{})
+		credsSourceCfg := d.Get(credentialsSourceName).([]interface{})
+		oidcCfg := d.Get(oidcConfigName).([]interface{})
 
-		var token bridgeapi.TokenSource
+		var (
+			token             bridgeapi.TokenSource
+			execAllowRelative bool
+		)
 
 		switch {
 		case id != "" && secret != "":
 			token = bridgeapi.NewLegacyAuth(id, secret)
 		case apiKey != "":
 			token = bridgeapi.APIKeyAuth(apiKey)
+		case len(credsSourceCfg) > 0:
+			var diags diag.Diagnostics
+
+			token, diags = buildSecretTokenSource(ctx, credsSourceCfg[0].(map[string]interface{}))
+			if diags.HasError() {
+				return nil, diags
+			}
+		case len(execCfg) > 0:
+			cfg := execCfg[0].(map[string]interface{})
+
+			command := cfg["command"].(string)
+
+			var args []string
+			for _, a := range cfg["args"].([]interface{}) {
+				args = append(args, a.(string))
+			}
+
+			env := make(map[string]string)
+			for k, v := range cfg["env"].(map[string]interface{}) {
+				env[k] = v.(string)
+			}
+
+			timeout := time.Duration(cfg["timeout_seconds"].(int)) * time.Second
+			execAllowRelative = cfg["allow_relative_path"].(bool)
+
+			token = bridgeapi.NewExecTokenSource(command, args, env, timeout)
+		case len(oidcCfg) > 0:
+			cfg := oidcCfg[0].(map[string]interface{})
+
+			headers := make(map[string]string)
+			for k, v := range cfg["headers"].(map[string]interface{}) {
+				headers[k] = v.(string)
+			}
+
+			source := bridgeapi.CredentialSource{
+				URL:     cfg["token_url"].(string),
+				Headers: headers,
+				File:    cfg["token_file"].(string),
+				EnvVar:  cfg["token_env"].(string),
+			}
+
+			token = bridgeapi.NewFederatedAuth(source, cfg["audience"].(string), cfg["subject_token_type"].(string))
 		default:
 			return nil, diag.Errorf(
-				"either supply %q or %q and %q for authentication",
-				apiKeyConfigName, idConfigName, secretConfigName)
+				"either supply %q, %q and %q, %q, %q, or %q for authentication",
+				apiKeyConfigName, idConfigName, secretConfigName, execConfigName, credentialsSourceName, oidcConfigName)
 		}
 
 		apiUrl, err := url.Parse(d.Get(urlConfigName).(string))
@@ -139,6 +415,10 @@ func configure(version string, p *schema.Provider) func(context.Context, *schema
 			bridgeapi.WithUserAgent(userAgent),
 		}
 
+		if execAllowRelative {
+			options = append(options, bridgeapi.WithExecAllowRelativePath())
+		}
+
 		if immediateLogin {
 			options = append(options, bridgeapi.WithImmediateLogin())
 		}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// providerConfig configures just enough of the provider to plan successfully
+// without talking to a real Crunchy Bridge API.
+const providerConfig = `
+provider "crunchybridge" {
+  api_key       = "test-api-key"
+  bridgeapi_url = "https://api.crunchybridge.example"
+}
+`
+
+// protoV5ProviderFactories serves the SDKv2 provider built by New directly
+// over protocol 5, the protocol Terraform CLI speaks before any upgrading or
+// muxing happens.
+var protoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+	"crunchybridge": func() (tfprotov5.ProviderServer, error) {
+		return New("test")().GRPCProvider(), nil
+	},
+}
+
+// protoV6ProviderFactories serves MuxServer, the protocol 6 server that
+// combines the protocol-5-to-6-upgraded SDKv2 provider with the framework
+// provider. A mismatch between the two muxed schemas (see framework.go's
+// Schema) fails GetProviderSchema here the same way it would for a real
+// user running terraform init or plan.
+var protoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"crunchybridge": func() (tfprotov6.ProviderServer, error) {
+		return MuxServer(context.Background(), "test")()
+	},
+}
+
+// TestAccProviderSchema_protoV5 plans providerConfig against the bare SDKv2
+// provider, establishing the baseline plan that the muxed protocol 6 server
+// below must match.
+func TestAccProviderSchema_protoV5(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:   providerConfig,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// TestAccProviderSchema_protoV6 plans the same providerConfig against
+// MuxServer. If framework.go's Schema ever drifts from New's schema, the mux
+// rejects GetProviderSchema and this test fails instead of silently breaking
+// terraform init/plan for every user, per the stage-one migration plan in
+// framework.go.
+func TestAccProviderSchema_protoV6(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:   providerConfig,
+				PlanOnly: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2023 Crunchy Data Solutions, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/CrunchyData/terraform-provider-crunchybridge/internal/bridgeapi"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCluster() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceClusterCreate,
+		ReadContext:   resourceClusterRead,
+		UpdateContext: resourceClusterUpdate,
+		DeleteContext: resourceClusterDelete,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "A human-readable name for the cluster.",
+			},
+			"team": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the team that owns the cluster.",
+			},
+			"plan": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The plan id, e.g. `standard-8`.",
+			},
+			"provider_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The cloud provider to create the cluster on.",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The cloud provider region to create the cluster in.",
+			},
+			"storage": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Storage size in GB.",
+			},
+			"is_ha": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Whether the cluster has a high-availability replica.",
+			},
+			"postgres_version": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The major Postgres version to provision.",
+			},
+		},
+	}
+}
+
+func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*bridgeapi.Client)
+
+	cluster, err := c.CreateCluster(ctx, bridgeapi.Cluster{
+		Name:            d.Get("name").(string),
+		TeamID:          d.Get("team").(string),
+		PlanID:          d.Get("plan").(string),
+		ProviderID:      d.Get("provider_id").(string),
+		Region:          d.Get("region").(string),
+		Storage:         d.Get("storage").(int),
+		IsHA:            d.Get("is_ha").(bool),
+		PostgresVersion: d.Get("postgres_version").(int),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Set the id as soon as we have it so that a failure in
+	// WaitForCluster below still lets Terraform taint and retry the
+	// resource instead of losing track of it entirely.
+	d.SetId(cluster.ID)
+
+	if err := c.WaitForCluster(ctx, cluster.ID, bridgeapi.WaitOptions{}); err != nil {
+		return diag.FromErr(fmt.Errorf("cluster %s did not become ready: %w", cluster.ID, err))
+	}
+
+	return resourceClusterRead(ctx, d, meta)
+}
+
+func resourceClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*bridgeapi.Client)
+
+	cluster, err := c.GetCluster(ctx, d.Id())
+	if errors.Is(err, bridgeapi.ErrClusterNotFound) {
+		// The cluster was deleted out-of-band; drop it from state instead
+		// of hard-erroring on every future plan/refresh.
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+
+	for k, v := range map[string]interface{}{
+		"name":             cluster.Name,
+		"team":             cluster.TeamID,
+		"plan":             cluster.PlanID,
+		"provider_id":      cluster.ProviderID,
+		"region":           cluster.Region,
+		"storage":          cluster.Storage,
+		"is_ha":            cluster.IsHA,
+		"postgres_version": cluster.PostgresVersion,
+	} {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+func resourceClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*bridgeapi.Client)
+
+	if d.HasChange("name") {
+		if _, err := c.UpdateClusterName(ctx, d.Id(), d.Get("name").(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("plan") || d.HasChange("storage") || d.HasChange("is_ha") {
+		isHA := d.Get("is_ha").(bool)
+
+		if _, err := c.UpgradeCluster(ctx, d.Id(), bridgeapi.ClusterUpgradeRequest{
+			PlanID:  d.Get("plan").(string),
+			Storage: d.Get("storage").(int),
+			IsHA:    &isHA,
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := c.WaitForCluster(ctx, d.Id(), bridgeapi.WaitOptions{}); err != nil {
+			return diag.FromErr(fmt.Errorf("cluster %s did not finish upgrading: %w", d.Id(), err))
+		}
+	}
+
+	return resourceClusterRead(ctx, d, meta)
+}
+
+func resourceClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*bridgeapi.Client)
+
+	if err := c.DeleteCluster(ctx, d.Id()); err != nil && !errors.Is(err, bridgeapi.ErrClusterNotFound) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}